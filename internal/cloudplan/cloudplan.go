@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cloudplan deals with "saved plan bookmarks": small JSON files that
+// a cloud/TFC-backed `terraform plan -out=...` writes in place of a full
+// local plan file. A bookmark doesn't contain plan data itself -- it just
+// points at the run that produced the plan, so that later commands (chiefly
+// `terraform show` and `terraform apply`) can go back to the backend and
+// fetch the real data.
+package cloudplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// bookmarkMagic is the value of a saved plan bookmark file's "magic" field.
+// After decoding a file as JSON, OpenSavedPlanBookmark checks for it so that
+// other JSON documents (a state file, for instance, which has no "magic"
+// field) are not mistaken for a bookmark.
+const bookmarkMagic = "tfc-saved-plan-bookmark"
+
+// SavedPlanBookmark is the decoded contents of a saved plan bookmark file.
+// It identifies exactly one run in exactly one cloud/TFC workspace.
+type SavedPlanBookmark struct {
+	Magic        string `json:"magic"`
+	Hostname     string `json:"hostname"`
+	Organization string `json:"organization"`
+	Workspace    string `json:"workspace"`
+	RunID        string `json:"run_id"`
+}
+
+// OpenSavedPlanBookmark reads and decodes a saved plan bookmark from the
+// given path. It returns an error satisfying IsErrInvalidBookmark if the
+// file exists but isn't a bookmark at all, so that callers can fall back to
+// treating the path as a local plan file or state file instead.
+func OpenSavedPlanBookmark(path string) (SavedPlanBookmark, error) {
+	var bookmark SavedPlanBookmark
+
+	f, err := os.Open(path)
+	if err != nil {
+		return bookmark, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&bookmark); err != nil {
+		return bookmark, &errInvalidBookmark{path: path, cause: err}
+	}
+	if bookmark.Magic != bookmarkMagic {
+		return bookmark, &errInvalidBookmark{path: path, cause: fmt.Errorf("missing %q magic marker", bookmarkMagic)}
+	}
+	return bookmark, nil
+}
+
+// errInvalidBookmark indicates that a file was read but is not a saved plan
+// bookmark: either it isn't JSON at all, or it is JSON without the bookmark
+// magic marker.
+type errInvalidBookmark struct {
+	path  string
+	cause error
+}
+
+func (e *errInvalidBookmark) Error() string {
+	return fmt.Sprintf("%s is not a saved plan bookmark: %s", e.path, e.cause)
+}
+
+func (e *errInvalidBookmark) Unwrap() error {
+	return e.cause
+}
+
+// IsErrInvalidBookmark reports whether err indicates that a path is not a
+// saved plan bookmark file, as opposed to some other failure (network error,
+// permission denied, mismatched workspace, etc).
+func IsErrInvalidBookmark(err error) bool {
+	_, ok := err.(*errInvalidBookmark)
+	return ok
+}
+
+// ErrMismatchedWorkspace is returned by callers that resolve a bookmark
+// against the currently-configured cloud backend when the bookmark points at
+// a different organization/workspace than the one Terraform is currently
+// configured to use. It's kept as a distinct type (rather than a generic
+// fmt.Errorf) so that command code can produce a targeted diagnostic instead
+// of a generic "failed to read" error.
+type ErrMismatchedWorkspace struct {
+	BookmarkOrg, BookmarkWorkspace     string
+	ConfiguredOrg, ConfiguredWorkspace string
+}
+
+func (e *ErrMismatchedWorkspace) Error() string {
+	return fmt.Sprintf(
+		"the saved plan bookmark refers to workspace %q in organization %q, but the currently-configured cloud backend is using workspace %q in organization %q",
+		e.BookmarkWorkspace, e.BookmarkOrg, e.ConfiguredWorkspace, e.ConfiguredOrg,
+	)
+}
+
+// Validate checks that the bookmark refers to the same organization and
+// workspace that the given configured backend is using, returning
+// *ErrMismatchedWorkspace if not.
+func (b SavedPlanBookmark) Validate(configuredOrg, configuredWorkspace string) error {
+	if b.Organization != configuredOrg || b.Workspace != configuredWorkspace {
+		return &ErrMismatchedWorkspace{
+			BookmarkOrg:         b.Organization,
+			BookmarkWorkspace:   b.Workspace,
+			ConfiguredOrg:       configuredOrg,
+			ConfiguredWorkspace: configuredWorkspace,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloudplan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenSavedPlanBookmark(t *testing.T) {
+	t.Run("valid bookmark", func(t *testing.T) {
+		path := writeBookmarkFile(t, `{"magic":"tfc-saved-plan-bookmark","hostname":"app.terraform.io","organization":"example-corp","workspace":"prod","run_id":"run-abc123"}`)
+
+		bookmark, err := OpenSavedPlanBookmark(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if bookmark.Organization != "example-corp" || bookmark.Workspace != "prod" || bookmark.RunID != "run-abc123" {
+			t.Fatalf("unexpected bookmark: %#v", bookmark)
+		}
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		path := writeBookmarkFile(t, "PK\x03\x04this is actually a zip file")
+
+		_, err := OpenSavedPlanBookmark(path)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !IsErrInvalidBookmark(err) {
+			t.Fatalf("expected IsErrInvalidBookmark to be true, got: %s", err)
+		}
+	})
+
+	t.Run("json but missing magic", func(t *testing.T) {
+		path := writeBookmarkFile(t, `{"hostname":"app.terraform.io","organization":"example-corp","workspace":"prod","run_id":"run-abc123"}`)
+
+		_, err := OpenSavedPlanBookmark(path)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !IsErrInvalidBookmark(err) {
+			t.Fatalf("expected IsErrInvalidBookmark to be true, got: %s", err)
+		}
+	})
+
+	t.Run("nonexistent file", func(t *testing.T) {
+		_, err := OpenSavedPlanBookmark(filepath.Join(t.TempDir(), "does-not-exist"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if IsErrInvalidBookmark(err) {
+			t.Fatal("expected IsErrInvalidBookmark to be false for an I/O error")
+		}
+	})
+}
+
+func TestSavedPlanBookmark_Validate(t *testing.T) {
+	bookmark := SavedPlanBookmark{
+		Magic:        bookmarkMagic,
+		Organization: "example-corp",
+		Workspace:    "prod",
+		RunID:        "run-abc123",
+	}
+
+	if err := bookmark.Validate("example-corp", "prod"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err := bookmark.Validate("other-corp", "prod")
+	if err == nil {
+		t.Fatal("expected an error for a mismatched organization")
+	}
+	mismatch, ok := err.(*ErrMismatchedWorkspace)
+	if !ok {
+		t.Fatalf("expected *ErrMismatchedWorkspace, got %T", err)
+	}
+	if mismatch.ConfiguredOrg != "other-corp" || mismatch.BookmarkOrg != "example-corp" {
+		t.Fatalf("unexpected mismatch details: %#v", mismatch)
+	}
+
+	if err := bookmark.Validate("example-corp", "staging"); err == nil {
+		t.Fatal("expected an error for a mismatched workspace")
+	}
+}
+
+func writeBookmarkFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bookmark.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write test bookmark file: %s", err)
+	}
+	return path
+}
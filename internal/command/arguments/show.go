@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"flag"
+
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// Show represents the command-line arguments for the show command, once
+// ParseView has already stripped out the global view arguments (such as
+// -no-color) that every command shares. -json is parsed by ParseShow itself.
+type Show struct {
+	// Path is an optional path to a state or plan file. An empty Path means
+	// show the latest state snapshot from the configured backend instead.
+	Path string
+
+	// ViewType selects which view should render the result: human-readable
+	// or machine-readable JSON.
+	ViewType ViewType
+
+	// State selects which state snapshot to display when Path points at a
+	// local plan file: the default "planned" snapshot, or the "prior"
+	// snapshot from before planning. It has no effect for any other kind of
+	// argument.
+	State ShowStateSnapshot
+}
+
+// ParseShow processes CLI arguments, returning a Show value and errors. It
+// expects the arguments it's given to have already had any global view
+// arguments removed by ParseView.
+func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	show := &Show{
+		ViewType: ViewHuman,
+		State:    ShowStatePlanned,
+	}
+
+	var jsonOutput bool
+	var stateRaw string
+
+	cmdFlags := flag.NewFlagSet("show", flag.ContinueOnError)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.StringVar(&stateRaw, "state", "", "which embedded state snapshot to display")
+	cmdFlags.Usage = func() {}
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+		return show, diags
+	}
+
+	state, err := ParseShowStateSnapshot(stateRaw)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid -state argument",
+			err.Error(),
+		))
+		return show, diags
+	}
+	show.State = state
+
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"Expected at most one positional argument: a path to a state or plan file.",
+		))
+		return show, diags
+	}
+	if len(args) == 1 {
+		show.Path = args[0]
+	}
+
+	if jsonOutput {
+		show.ViewType = ViewJSON
+	}
+
+	return show, diags
+}
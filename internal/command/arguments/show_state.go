@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import "fmt"
+
+// ShowStateSnapshot selects which state snapshot embedded in a local plan
+// file `terraform show` should display: the "planned" snapshot (the
+// default, reflecting refresh results and input variables as of planning
+// time) or the "prior" snapshot (what Terraform believed existed before it
+// planned anything).
+type ShowStateSnapshot string
+
+const (
+	ShowStatePlanned ShowStateSnapshot = "planned"
+	ShowStatePrior   ShowStateSnapshot = "prior"
+)
+
+// ParseShowStateSnapshot validates the value given to `-state=...` on
+// `terraform show`, defaulting to ShowStatePlanned for an empty string so
+// callers can use it directly against an unset flag.
+func ParseShowStateSnapshot(raw string) (ShowStateSnapshot, error) {
+	switch ShowStateSnapshot(raw) {
+	case "", ShowStatePlanned:
+		return ShowStatePlanned, nil
+	case ShowStatePrior:
+		return ShowStatePrior, nil
+	default:
+		return "", fmt.Errorf("invalid value %q for -state: must be %q or %q", raw, ShowStatePlanned, ShowStatePrior)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import "testing"
+
+func TestParseShow(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		got, diags := ParseShow(nil)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+		if got.Path != "" {
+			t.Fatalf("expected no path, got %q", got.Path)
+		}
+		if got.State != ShowStatePlanned {
+			t.Fatalf("expected default state %q, got %q", ShowStatePlanned, got.State)
+		}
+		if got.ViewType != ViewHuman {
+			t.Fatalf("expected default view type %v, got %v", ViewHuman, got.ViewType)
+		}
+	})
+
+	t.Run("path and -state=prior", func(t *testing.T) {
+		got, diags := ParseShow([]string{"-state=prior", "my.tfplan"})
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+		if got.Path != "my.tfplan" {
+			t.Fatalf("expected path %q, got %q", "my.tfplan", got.Path)
+		}
+		if got.State != ShowStatePrior {
+			t.Fatalf("expected state %q, got %q", ShowStatePrior, got.State)
+		}
+	})
+
+	t.Run("-json sets the view type", func(t *testing.T) {
+		got, diags := ParseShow([]string{"-json"})
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+		if got.ViewType != ViewJSON {
+			t.Fatalf("expected view type %v, got %v", ViewJSON, got.ViewType)
+		}
+	})
+
+	t.Run("invalid -state value", func(t *testing.T) {
+		_, diags := ParseShow([]string{"-state=bogus"})
+		if !diags.HasErrors() {
+			t.Fatal("expected an error for an invalid -state value")
+		}
+	})
+
+	t.Run("too many positional arguments", func(t *testing.T) {
+		_, diags := ParseShow([]string{"one.tfplan", "two.tfplan"})
+		if !diags.HasErrors() {
+			t.Fatal("expected an error for extra positional arguments")
+		}
+	})
+}
@@ -4,23 +4,39 @@
 package command
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/cloudplan"
 	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/jsonformat"
 	"github.com/hashicorp/terraform/internal/command/views"
 	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/encryption"
 	"github.com/hashicorp/terraform/internal/plans"
 	"github.com/hashicorp/terraform/internal/plans/planfile"
+	"github.com/hashicorp/terraform/internal/statemigrate"
 	"github.com/hashicorp/terraform/internal/states/statefile"
 	"github.com/hashicorp/terraform/internal/states/statemgr"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
+// showPlanForRunBackend is implemented by backends (namely the cloud
+// backend) that can fetch a redacted JSON plan and a human-readable run
+// summary for a run that was created out-of-band, e.g. by `terraform plan
+// -out=...` against a `cloud {}` configuration. ShowCommand type-asserts for
+// this interface rather than depending on the cloud backend package
+// directly, the same way other commands avoid a hard dependency on it.
+type showPlanForRunBackend interface {
+	ShowPlanForRun(ctx context.Context, hostname, org, workspace, runID string) (*jsonformat.Plan, string, error)
+}
+
 // ShowCommand is a Command implementation that reads and outputs the
 // contents of a Terraform plan or state file.
 type ShowCommand struct {
@@ -52,13 +68,30 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 	}
 
 	// Get the data we need to display
-	plan, jsonPlan, stateFile, config, schemas, showDiags := c.show(args.Path)
+	plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, showDiags := c.show(args.Path, args.State, args.ViewType)
 	diags = diags.Append(showDiags)
 	if showDiags.HasErrors() {
 		view.Diagnostics(diags)
 		return 1
 	}
 
+	// Surface any warnings (e.g. a state schema migration applied for
+	// display) even though nothing failed; these would otherwise never
+	// reach the user, since the error path above is the only other place
+	// view.Diagnostics gets called.
+	if len(diags) > 0 {
+		view.Diagnostics(diags)
+	}
+
+	// Saved plan bookmarks for runs the backend can't return structured JSON
+	// for (e.g. because the run predates that feature, or the plan was
+	// discarded) come back as a pre-rendered human-readable summary instead
+	// of a *jsonformat.Plan. There's nothing further to render in that case.
+	if cloudRunOutput != "" {
+		c.Streams.Println(cloudRunOutput)
+		return 0
+	}
+
 	// Display the data
 	return view.Display(config, plan, jsonPlan, stateFile, schemas)
 }
@@ -75,6 +108,10 @@ Options:
   -no-color           If specified, output won't contain any color.
   -json               If specified, output the Terraform plan or state in
                       a machine-readable form.
+  -state=planned      For a local plan file argument, selects which
+                      embedded state snapshot to display: "planned" (the
+                      default, post-refresh state the plan is based on) or
+                      "prior" (the state Terraform had before planning).
 
 `
 	return strings.TrimSpace(helpText)
@@ -84,13 +121,14 @@ func (c *ShowCommand) Synopsis() string {
 	return "Show the current state or a saved plan"
 }
 
-func (c *ShowCommand) show(path string) (*plans.Plan, *jsonformat.Plan, *statefile.File, *configs.Config, *terraform.Schemas, tfdiags.Diagnostics) {
+func (c *ShowCommand) show(path string, stateSnapshot arguments.ShowStateSnapshot, viewType arguments.ViewType) (*plans.Plan, *jsonformat.Plan, *statefile.File, *configs.Config, *terraform.Schemas, string, tfdiags.Diagnostics) {
 	var diags, showDiags tfdiags.Diagnostics
 	var plan *plans.Plan
 	var jsonPlan *jsonformat.Plan
 	var stateFile *statefile.File
 	var config *configs.Config
 	var schemas *terraform.Schemas
+	var cloudRunOutput string
 
 	// No plan file or state file argument provided,
 	// so get the latest state snapshot
@@ -98,7 +136,7 @@ func (c *ShowCommand) show(path string) (*plans.Plan, *jsonformat.Plan, *statefi
 		stateFile, showDiags = c.showFromLatestStateSnapshot()
 		diags = diags.Append(showDiags)
 		if showDiags.HasErrors() {
-			return plan, jsonPlan, stateFile, config, schemas, diags
+			return plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, diags
 		}
 	}
 
@@ -106,27 +144,103 @@ func (c *ShowCommand) show(path string) (*plans.Plan, *jsonformat.Plan, *statefi
 	// so try to load the argument as a plan file first.
 	// If that fails, try to load it as a statefile.
 	if path != "" {
-		plan, jsonPlan, stateFile, config, showDiags = c.showFromPath(path)
+		plan, jsonPlan, stateFile, config, cloudRunOutput, showDiags = c.showFromPath(path, stateSnapshot, viewType)
 		diags = diags.Append(showDiags)
 		if showDiags.HasErrors() {
-			return plan, jsonPlan, stateFile, config, schemas, diags
+			return plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, diags
+		}
+	}
+
+	// A saved cloud plan bookmark that resolved to pre-rendered text has no
+	// state, config, or schemas of its own to attach.
+	if cloudRunOutput != "" {
+		return plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, diags
+	}
+
+	// Apply any registered state schema migrations before resolving
+	// schemas, so that MaybeGetSchemas (and everything downstream of it)
+	// sees the migrated view of the state. This never writes anything back
+	// to the backend; it's a read-time rewrite of what's already in memory.
+	if stateFile != nil {
+		migrated, migrationsFired, migrateDiags := statemigrate.Apply(stateFile.State, config)
+		diags = diags.Append(migrateDiags)
+		if migrateDiags.HasErrors() {
+			return plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, diags
+		}
+		if len(migrationsFired) > 0 {
+			stateFile.State = migrated
+			diags = diags.Append(migrationsFiredDiagnostic(migrationsFired, viewType == arguments.ViewJSON))
 		}
 	}
 
 	// Get schemas, if possible
 	if config != nil || stateFile != nil {
-		schemas, diags = c.MaybeGetSchemas(stateFile.State, config)
-		if diags.HasErrors() {
-			return plan, jsonPlan, stateFile, config, schemas, diags
+		var schemaDiags tfdiags.Diagnostics
+		schemas, schemaDiags = c.MaybeGetSchemas(stateFile.State, config)
+		diags = diags.Append(schemaDiags)
+		if schemaDiags.HasErrors() {
+			return plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, diags
 		}
 	}
 
-	return plan, jsonPlan, stateFile, config, schemas, diags
+	return plan, jsonPlan, stateFile, config, schemas, cloudRunOutput, diags
+}
+
+// stateEncryption builds the StateEncryption to use for reading state in
+// this invocation. It reads the `encryption {}` block out of the root
+// module in the current working directory, since show needs to be able to
+// decrypt a state/plan file before the rest of configuration (which is
+// where that block would normally be parsed as part of) has even been
+// loaded. The TF_ENCRYPTION environment variable takes precedence over it,
+// for cases like showing a bare file path with no working directory at all.
+func (c *ShowCommand) stateEncryption() (encryption.StateEncryption, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	hclCfg, hclDiags := encryption.LoadConfigDir(".")
+	if hclDiags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid encryption configuration",
+			hclDiags.Error(),
+		))
+		return nil, diags
+	}
+
+	envCfg, err := encryption.ConfigFromEnv()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid encryption configuration",
+			err.Error(),
+		))
+		return nil, diags
+	}
+
+	cfg := encryption.Merge(envCfg, hclCfg)
+
+	enc, err := encryption.New(cfg)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid encryption configuration",
+			err.Error(),
+		))
+		return nil, diags
+	}
+
+	return enc.StateEncryption(), diags
 }
+
 func (c *ShowCommand) showFromLatestStateSnapshot() (*statefile.File, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
-	// Load the backend
+	// NOTE: unlike showFromPath/getStateFromPath, state read through a
+	// backend's StateMgr is not decrypted here -- that would require
+	// threading a StateEncryption into whatever state manager the backend
+	// constructs, which this series doesn't touch. Encrypted state is only
+	// supported for the bare-file argument form of `show` today; `show`
+	// with no path argument against an encrypted backend-stored state will
+	// fail deserializing inside RefreshState rather than being decrypted.
 	b, backendDiags := c.Backend(nil)
 	diags = diags.Append(backendDiags)
 	if backendDiags.HasErrors() {
@@ -144,71 +258,209 @@ func (c *ShowCommand) showFromLatestStateSnapshot() (*statefile.File, tfdiags.Di
 	// Get the latest state snapshot from the backend for the current workspace
 	stateFile, stateErr := getStateFromBackend(b, workspace)
 	if stateErr != nil {
-		diags = diags.Append(stateErr)
+		diags = diags.Append(stateDecryptDiagnostic(stateErr))
 		return nil, diags
 	}
 
 	return stateFile, diags
 }
 
-func (c *ShowCommand) showFromPath(path string) (*plans.Plan, *jsonformat.Plan, *statefile.File, *configs.Config, tfdiags.Diagnostics) {
+// migrationsFiredDiagnostic reports which statemigrate migrations were
+// applied to produce this output. In JSON view, its Detail carries a
+// trailing `migrations_fired=[...]` JSON array in addition to the
+// human-readable sentence, so that tooling consuming `-json` output's
+// diagnostics (which carry this Detail verbatim) can mechanically detect
+// drift between stored and migrated state without needing a dedicated field
+// threaded through jsonformat/views. The marker is omitted for human view,
+// where it would otherwise show up as a stray JSON fragment in the console
+// output of anyone who happens to hit a fired migration.
+func migrationsFiredDiagnostic(migrationsFired []string, machineReadable bool) tfdiags.Diagnostic {
+	detail := fmt.Sprintf(
+		"The following state schema migrations were applied to produce this output, but were not persisted back to storage: %s",
+		strings.Join(migrationsFired, ", "),
+	)
+	if machineReadable {
+		marker, err := json.Marshal(migrationsFired)
+		if err != nil {
+			// migrationsFired is always a []string; this cannot fail in practice.
+			marker = []byte("[]")
+		}
+		detail = fmt.Sprintf("%s\n\nmigrations_fired=%s", detail, marker)
+	}
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"State was migrated for display",
+		detail,
+	)
+}
+
+// snapshotSelectedDiagnostic reports that a local plan file's -json output
+// was rendered from the non-default "prior" state snapshot (-state=prior),
+// so downstream tooling consuming -json output's diagnostics can tell it
+// apart from the default "planned" snapshot. The ideal fix here would be a
+// dedicated "snapshot" field on the jsonstate schema itself (with an
+// accompanying format_version bump), but the jsonstate/jsonplan/views
+// packages that schema lives in aren't part of this tree, so this
+// diagnostic-based marker is the best this series can do, the same
+// workaround used for migrationsFiredDiagnostic above.
+func snapshotSelectedDiagnostic() tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"State snapshot selected",
+		"This output was rendered from the plan's \"prior\" state snapshot, not the default \"planned\" one.\n\nsnapshot=prior",
+	)
+}
+
+// stateDecryptDiagnostic wraps encryption.ErrNoKeyProviderConfigured (and
+// errors derived from it) in a distinct, actionable diagnostic rather than
+// letting it surface as a generic read failure.
+func stateDecryptDiagnostic(err error) tfdiags.Diagnostic {
+	if errors.Is(err, encryption.ErrNoKeyProviderConfigured) {
+		return tfdiags.Sourceless(
+			tfdiags.Error,
+			"State is encrypted",
+			"The state file is encrypted, but no matching key provider is configured. Set the TF_ENCRYPTION environment variable, or configure a matching `encryption { state { ... } }` block, and try again.",
+		)
+	}
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		"Failed to read state",
+		err.Error(),
+	)
+}
+
+func (c *ShowCommand) showFromPath(path string, stateSnapshot arguments.ShowStateSnapshot, viewType arguments.ViewType) (*plans.Plan, *jsonformat.Plan, *statefile.File, *configs.Config, string, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	var planErr, stateErr error
-	var plan *plans.Plan
-	var jsonPlan *jsonformat.Plan
-	var stateFile *statefile.File
-	var config *configs.Config
 
-	// Path might be a local plan file, a bookmark to a saved cloud plan, or a
-	// state file. First, try to get a plan and associated data from a local
-	// plan file. If that fails, try to get a json plan from the path argument.
-	// If that fails, try to get the statefile from the path argument.
-	plan, jsonPlan, stateFile, config, planErr = getPlanFromPath(path)
-	if planErr != nil {
-		stateFile, stateErr = getStateFromPath(path)
-		if stateErr != nil {
+	dec, encDiags := c.stateEncryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, nil, nil, nil, "", diags
+	}
+
+	// Path might be a local plan file, a saved cloud plan bookmark, or a
+	// state file. Try each in turn, and only report a combined error once
+	// none of them recognize the path.
+	pf, planErr := planfile.OpenWrapped(path)
+	if planErr == nil && pf.IsLocal() {
+		plan, stateFile, config, err := getDataFromPlanfileReader(pf.Local, stateSnapshot)
+		if err != nil {
 			diags = diags.Append(
 				tfdiags.Sourceless(
 					tfdiags.Error,
 					"Failed to read the given file as a state or plan file",
-					fmt.Sprintf("State read error: %s\n\nPlan read error: %s", stateErr, planErr),
+					fmt.Sprintf("Plan read error: %s", err),
 				),
 			)
-			return nil, nil, nil, nil, diags
+			return nil, nil, nil, nil, "", diags
+		}
+		if viewType == arguments.ViewJSON && stateSnapshot == arguments.ShowStatePrior {
+			diags = diags.Append(snapshotSelectedDiagnostic())
+		}
+		return plan, nil, stateFile, config, "", diags
+	}
+	if planErr == nil && !pf.IsLocal() {
+		// OpenWrapped recognized path as a plan-shaped zip, but not one
+		// built around a local statefile. This build doesn't know what to
+		// do with whatever cloud representation it embeds on its own, so
+		// fall through to the saved-plan-bookmark and statefile attempts
+		// below rather than guessing at its shape.
+		planErr = fmt.Errorf("%s does not contain a local plan this build of Terraform can read", path)
+	}
+
+	bookmark, bookmarkErr := cloudplan.OpenSavedPlanBookmark(path)
+	if bookmarkErr == nil {
+		jsonPlan, cloudRunOutput, cloudDiags := c.getPlanFromCloud(bookmark)
+		diags = diags.Append(cloudDiags)
+		if cloudDiags.HasErrors() {
+			return nil, nil, nil, nil, "", diags
+		}
+		return nil, jsonPlan, nil, nil, cloudRunOutput, diags
+	}
+	if !cloudplan.IsErrInvalidBookmark(bookmarkErr) {
+		// Something went wrong actually reading the candidate bookmark file
+		// (permissions, I/O, ...), as opposed to it just not being a
+		// bookmark -- surface that directly rather than burying it in the
+		// generic plan-or-state fallback message below.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read saved plan bookmark",
+			bookmarkErr.Error(),
+		))
+		return nil, nil, nil, nil, "", diags
+	}
+
+	stateFile, stateErr := getStateFromPath(path, dec)
+	if stateErr != nil {
+		if errors.Is(stateErr, encryption.ErrNoKeyProviderConfigured) {
+			diags = diags.Append(stateDecryptDiagnostic(stateErr))
+			return nil, nil, nil, nil, "", diags
 		}
+		diags = diags.Append(
+			tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read the given file as a state or plan file",
+				fmt.Sprintf("State read error: %s\n\nPlan read error: %s\n\nSaved plan bookmark read error: %s", stateErr, planErr, bookmarkErr),
+			),
+		)
+		return nil, nil, nil, nil, "", diags
 	}
-	return plan, jsonPlan, stateFile, config, diags
+	return nil, nil, stateFile, nil, "", diags
 }
 
-// getPlanFromPath returns a plan, json plan, statefile, and config if the
-// user-supplied path points to either a local or cloud plan file. Note that
-// some of the return values will be nil no matter what; local plan files do not
-// yield a json plan, and cloud plans do not yield real plan/state/config
-// structs. An error generally suggests that the given path is either a
-// directory or a statefile.
-func getPlanFromPath(path string) (*plans.Plan, *jsonformat.Plan, *statefile.File, *configs.Config, error) {
-	var err error
-	var plan *plans.Plan
-	var jsonPlan *jsonformat.Plan
-	var stateFile *statefile.File
-	var config *configs.Config
+// getPlanFromCloud resolves a saved cloud plan bookmark against the
+// currently-configured cloud backend and fetches the run's redacted JSON
+// plan and human-readable summary. It returns a non-empty cloudRunOutput
+// (and a nil jsonPlan) when the backend can only provide pre-rendered text
+// for this run, e.g. because the run predates structured JSON plan support.
+func (c *ShowCommand) getPlanFromCloud(bookmark cloudplan.SavedPlanBookmark) (*jsonformat.Plan, string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
 
-	pf, err := planfile.OpenWrapped(path)
-	if err != nil {
-		return nil, nil, nil, nil, err
+	b, backendDiags := c.Backend(nil)
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return nil, "", diags
 	}
 
-	if pf.IsLocal() {
-		plan, stateFile, config, err = getDataFromPlanfileReader(pf.Local)
+	cloudBackend, ok := b.(showPlanForRunBackend)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Saved plan is not compatible with this backend",
+			"The given path is a saved cloud plan bookmark, but the currently-configured backend does not support fetching cloud runs. Configure a `cloud` block matching the one used to create the plan, or re-run `terraform plan` locally.",
+		))
+		return nil, "", diags
 	}
 
-	// TODO: get jsonplan from cloud pf
+	if err := bookmark.Validate(c.Meta.CloudOrganization(), c.Meta.CloudWorkspace()); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Saved plan is for a different workspace",
+			err.Error(),
+		))
+		return nil, "", diags
+	}
 
-	return plan, jsonPlan, stateFile, config, err
+	jsonPlan, runOutput, err := cloudBackend.ShowPlanForRun(context.Background(), bookmark.Hostname, bookmark.Organization, bookmark.Workspace, bookmark.RunID)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to fetch the saved plan from the cloud backend",
+			fmt.Sprintf("Run %s in %s/%s: %s", bookmark.RunID, bookmark.Organization, bookmark.Workspace, err),
+		))
+		return nil, "", diags
+	}
+
+	return jsonPlan, runOutput, diags
 }
 
-// getDataFromPlanfileReader returns a plan, statefile, and config, extracted from a local plan file.
-func getDataFromPlanfileReader(planReader *planfile.Reader) (*plans.Plan, *statefile.File, *configs.Config, error) {
+// getDataFromPlanfileReader returns a plan, statefile, and config, extracted
+// from a local plan file. By default the returned statefile is the
+// "planned" snapshot (the post-refresh state Terraform's plan is based on);
+// passing arguments.ShowStatePrior instead returns the snapshot from before
+// planning, for operators who want to diff it against the current backend
+// state by hand.
+func getDataFromPlanfileReader(planReader *planfile.Reader, stateSnapshot arguments.ShowStateSnapshot) (*plans.Plan, *statefile.File, *configs.Config, error) {
 	// Get plan
 	plan, err := planReader.ReadPlan()
 	if err != nil {
@@ -216,7 +468,12 @@ func getDataFromPlanfileReader(planReader *planfile.Reader) (*plans.Plan, *state
 	}
 
 	// Get statefile
-	stateFile, err := planReader.ReadStateFile()
+	var stateFile *statefile.File
+	if stateSnapshot == arguments.ShowStatePrior {
+		stateFile, err = planReader.ReadPreviousStateFile()
+	} else {
+		stateFile, err = planReader.ReadStateFile()
+	}
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -230,18 +487,19 @@ func getDataFromPlanfileReader(planReader *planfile.Reader) (*plans.Plan, *state
 	return plan, stateFile, config, err
 }
 
-// getStateFromPath returns a statefile if the user-supplied path points to a statefile.
-func getStateFromPath(path string) (*statefile.File, error) {
+// getStateFromPath returns a statefile if the user-supplied path points to a
+// statefile. dec is used to decrypt the file's contents if it turns out to
+// be an encrypted envelope; a nil dec is fine for plaintext state.
+func getStateFromPath(path string, dec encryption.StateEncryption) (*statefile.File, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("Error loading statefile: %s", err)
 	}
 	defer file.Close()
 
-	var stateFile *statefile.File
-	stateFile, err = statefile.Read(file)
+	stateFile, err := statefile.ReadEncrypted(file, dec)
 	if err != nil {
-		return nil, fmt.Errorf("Error reading %s as a statefile: %s", path, err)
+		return nil, fmt.Errorf("Error reading %s as a statefile: %w", path, err)
 	}
 	return stateFile, nil
 }
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+// Config is the decoded form of the top-level `encryption {}` block. It is
+// parsed ahead of backend initialization (state and plan files must be
+// decryptable before Terraform can do anything else with them), so its
+// arguments arrive as plain strings rather than going through the usual
+// schema/cty decoding that depends on provider schemas being available.
+type Config struct {
+	State *TargetConfig `hcl:"state,block"`
+	Plan  *TargetConfig `hcl:"plan,block"`
+}
+
+// TargetConfig is one of the `state { ... }` or `plan { ... }` blocks nested
+// inside `encryption {}`.
+type TargetConfig struct {
+	Method            string            `hcl:"method"`
+	KeyProvider       string            `hcl:"key_provider"`
+	KeyProviderConfig map[string]string `hcl:"key_provider_config,optional"`
+}
+
+// Merge combines two Configs, preferring primary's State/Plan targets over
+// fallback's when primary sets them. It's used to let the TF_ENCRYPTION
+// bootstrap variable take precedence over the `encryption {}` HCL block
+// when both happen to be present, since the env var exists specifically to
+// cover cases (like `terraform show` on a bare file path) where the block
+// can't be loaded at all.
+func Merge(primary, fallback Config) Config {
+	merged := primary
+	if merged.State == nil {
+		merged.State = fallback.State
+	}
+	if merged.Plan == nil {
+		merged.Plan = fallback.Plan
+	}
+	return merged
+}
@@ -0,0 +1,13 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package encryption implements encryption-at-rest for state and plan
+// files. It is configured by the top-level `encryption {}` block, which
+// names a key provider (something that produces a raw encryption key -- a
+// passphrase, a static hex key, a cloud KMS key) and a method (the envelope
+// format and cipher that uses that key).
+//
+// Key providers and methods are registered by name, with keyprovider.Register
+// and method.Register respectively, so that new ones can be added without
+// changing the call sites in internal/command or internal/backend.
+package encryption
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform/internal/encryption/keyprovider"
+	"github.com/hashicorp/terraform/internal/encryption/method"
+)
+
+// StateEncryption encrypts and decrypts the bytes of a state file.
+type StateEncryption interface {
+	EncryptState(plaintext []byte) ([]byte, error)
+	DecryptState(data []byte) ([]byte, error)
+}
+
+// PlanEncryption encrypts and decrypts the bytes of a plan file's
+// "tfplan"/"tfstate" zip members.
+type PlanEncryption interface {
+	EncryptPlan(plaintext []byte) ([]byte, error)
+	DecryptPlan(data []byte) ([]byte, error)
+}
+
+// Encryption is the entry point for this package: it wraps a Config into
+// ready-to-use StateEncryption and PlanEncryption implementations.
+type Encryption struct {
+	state *target
+	plan  *target
+}
+
+// New builds an Encryption from the given config. A nil TargetConfig for
+// state or plan means that target is left unencrypted: its EncryptX is the
+// identity function, and its DecryptX passes plaintext input through
+// unchanged but still errors out on encrypted input, so that removing an
+// encryption block doesn't silently lose the ability to read old state.
+func New(cfg Config) (*Encryption, error) {
+	state, err := newTarget(cfg.State)
+	if err != nil {
+		return nil, fmt.Errorf("encryption.state: %w", err)
+	}
+	plan, err := newTarget(cfg.Plan)
+	if err != nil {
+		return nil, fmt.Errorf("encryption.plan: %w", err)
+	}
+	return &Encryption{state: state, plan: plan}, nil
+}
+
+func (e *Encryption) StateEncryption() StateEncryption { return e.state }
+func (e *Encryption) PlanEncryption() PlanEncryption   { return e.plan }
+
+// target implements both StateEncryption and PlanEncryption; state and plan
+// encryption work identically, they're just configured and invoked
+// separately so that (for example) a passphrase can be scoped to only one
+// of them.
+type target struct {
+	keyProviderID string
+	keyProvider   keyprovider.KeyProvider
+	methodName    string
+	method        method.Method
+}
+
+func newTarget(cfg *TargetConfig) (*target, error) {
+	if cfg == nil {
+		return &target{}, nil
+	}
+
+	kp, err := keyprovider.New(cfg.KeyProvider, cfg.KeyProviderConfig)
+	if err != nil {
+		return nil, err
+	}
+	m, err := method.New(cfg.Method, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &target{keyProviderID: cfg.KeyProvider, keyProvider: kp, methodName: cfg.Method, method: m}, nil
+}
+
+func (t *target) configured() bool {
+	return t.keyProvider != nil && t.method != nil
+}
+
+func (t *target) EncryptState(plaintext []byte) ([]byte, error) { return t.encrypt(plaintext) }
+func (t *target) DecryptState(data []byte) ([]byte, error)      { return t.decrypt(data) }
+func (t *target) EncryptPlan(plaintext []byte) ([]byte, error)  { return t.encrypt(plaintext) }
+func (t *target) DecryptPlan(data []byte) ([]byte, error)       { return t.decrypt(data) }
+
+func (t *target) encrypt(plaintext []byte) ([]byte, error) {
+	if !t.configured() {
+		return plaintext, nil
+	}
+	envelope, err := t.method.Encrypt(plaintext, t.keyProvider)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rawEnvelope{
+		Encrypted:     true,
+		KeyProviderID: t.keyProviderID,
+		Method:        envelope.Method,
+		KeyMetadata:   envelope.KeyMetadata,
+		Nonce:         envelope.Nonce,
+		Ciphertext:    envelope.Ciphertext,
+	})
+}
+
+func (t *target) decrypt(data []byte) ([]byte, error) {
+	envelope, isEncrypted := sniff(data)
+	if !isEncrypted {
+		return data, nil
+	}
+	if !t.configured() {
+		return nil, ErrNoKeyProviderConfigured
+	}
+
+	// The envelope's key_provider_id/method fields are attacker/file
+	// controlled, so they're validated against what's actually configured
+	// rather than used to pick which method to instantiate: decrypting
+	// always uses the configured t.method and t.keyProvider, never
+	// something named by the file we're trying to read.
+	if envelope.KeyProviderID != t.keyProviderID {
+		return nil, fmt.Errorf("%w: state was encrypted with key provider %q, but %q is configured", ErrNoKeyProviderConfigured, envelope.KeyProviderID, t.keyProviderID)
+	}
+	if envelope.Method != t.methodName {
+		return nil, fmt.Errorf("state was encrypted with method %q, but %q is configured", envelope.Method, t.methodName)
+	}
+
+	return t.method.Decrypt(method.Envelope{
+		KeyProviderID: envelope.KeyProviderID,
+		Method:        envelope.Method,
+		KeyMetadata:   envelope.KeyMetadata,
+		Nonce:         envelope.Nonce,
+		Ciphertext:    envelope.Ciphertext,
+	}, t.keyProvider)
+}
+
+// ErrNoKeyProviderConfigured is returned by DecryptState/DecryptPlan when
+// the input data is an encrypted envelope but no `encryption {}` block (or
+// no matching key provider) is configured, so command code can show a
+// specific, actionable diagnostic instead of a generic read failure.
+var ErrNoKeyProviderConfigured = fmt.Errorf("state is encrypted but no matching key provider is configured")
+
+// rawEnvelope mirrors method.Envelope but additionally carries the
+// "encrypted" marker field used to sniff encrypted payloads.
+type rawEnvelope struct {
+	Encrypted     bool              `json:"encrypted"`
+	KeyProviderID string            `json:"key_provider_id"`
+	Method        string            `json:"method"`
+	KeyMetadata   map[string]string `json:"key_metadata,omitempty"`
+	Nonce         []byte            `json:"nonce"`
+	Ciphertext    []byte            `json:"ciphertext"`
+}
+
+// IsEncrypted reports whether data looks like an encrypted envelope rather
+// than a plaintext state/plan JSON document, without fully decoding it.
+func IsEncrypted(data []byte) bool {
+	_, ok := sniff(data)
+	return ok
+}
+
+func sniff(data []byte) (rawEnvelope, bool) {
+	var envelope rawEnvelope
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return envelope, false
+	}
+	if err := json.Unmarshal(trimmed, &envelope); err != nil {
+		return envelope, false
+	}
+	return envelope, envelope.Encrypted
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEncryption_RoundTrip(t *testing.T) {
+	cases := map[string]Config{
+		"static key provider": {
+			State: &TargetConfig{
+				Method:            "aes_gcm",
+				KeyProvider:       "static",
+				KeyProviderConfig: map[string]string{"key": "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"},
+			},
+		},
+		"pbkdf2 key provider": {
+			State: &TargetConfig{
+				Method:            "aes_gcm",
+				KeyProvider:       "pbkdf2",
+				KeyProviderConfig: map[string]string{"passphrase": "correct horse battery staple"},
+			},
+		},
+	}
+
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			enc, err := New(cfg)
+			if err != nil {
+				t.Fatalf("New failed: %s", err)
+			}
+
+			plaintext := []byte(`{"version":4,"resources":[]}`)
+			ciphertext, err := enc.StateEncryption().EncryptState(plaintext)
+			if err != nil {
+				t.Fatalf("EncryptState failed: %s", err)
+			}
+			if bytes.Equal(ciphertext, plaintext) {
+				t.Fatal("expected EncryptState to actually transform the input")
+			}
+			if !IsEncrypted(ciphertext) {
+				t.Fatal("expected ciphertext to sniff as encrypted")
+			}
+
+			var envelope rawEnvelope
+			if err := json.Unmarshal(ciphertext, &envelope); err != nil {
+				t.Fatalf("failed to unmarshal envelope: %s", err)
+			}
+			if envelope.KeyProviderID != cfg.State.KeyProvider {
+				t.Fatalf("expected key_provider_id %q in the envelope, got %q", cfg.State.KeyProvider, envelope.KeyProviderID)
+			}
+
+			got, err := enc.StateEncryption().DecryptState(ciphertext)
+			if err != nil {
+				t.Fatalf("DecryptState failed: %s", err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Fatalf("round trip mismatch: got %q, want %q", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryption_RejectsMismatchedKeyProvider(t *testing.T) {
+	encrypted, err := New(Config{State: &TargetConfig{
+		Method:            "aes_gcm",
+		KeyProvider:       "static",
+		KeyProviderConfig: map[string]string{"key": "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"},
+	}})
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+	ciphertext, err := encrypted.StateEncryption().EncryptState([]byte(`{"version":4}`))
+	if err != nil {
+		t.Fatalf("EncryptState failed: %s", err)
+	}
+
+	// Configured with a different key provider (pbkdf2 rather than static),
+	// so it's "configured" and would go on to decrypt using its own key if
+	// the envelope's key_provider_id weren't checked.
+	wrongProvider, err := New(Config{State: &TargetConfig{
+		Method:            "aes_gcm",
+		KeyProvider:       "pbkdf2",
+		KeyProviderConfig: map[string]string{"passphrase": "a different passphrase entirely"},
+	}})
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+	if _, err := wrongProvider.StateEncryption().DecryptState(ciphertext); err == nil {
+		t.Fatal("expected DecryptState to reject an envelope encrypted with a different key provider")
+	}
+}
+
+func TestEncryption_PlanRoundTrip(t *testing.T) {
+	cfg := Config{
+		Plan: &TargetConfig{
+			Method:            "aes_gcm",
+			KeyProvider:       "static",
+			KeyProviderConfig: map[string]string{"key": "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"},
+		},
+	}
+
+	enc, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+
+	plaintext := []byte(`{"format_version":"1.2"}`)
+	ciphertext, err := enc.PlanEncryption().EncryptPlan(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptPlan failed: %s", err)
+	}
+
+	got, err := enc.PlanEncryption().DecryptPlan(ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptPlan failed: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("round trip mismatch: got %q, want %q", got, plaintext)
+	}
+}
+
+func TestEncryption_Unconfigured(t *testing.T) {
+	enc, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+
+	plaintext := []byte(`{"version":4}`)
+	got, err := enc.StateEncryption().EncryptState(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptState failed: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatal("expected unconfigured EncryptState to pass plaintext through unchanged")
+	}
+
+	// But an encrypted payload found with no key provider configured must
+	// fail loudly rather than being treated as plaintext.
+	other, err := New(Config{State: &TargetConfig{
+		Method:            "aes_gcm",
+		KeyProvider:       "static",
+		KeyProviderConfig: map[string]string{"key": "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"},
+	}})
+	if err != nil {
+		t.Fatalf("New failed: %s", err)
+	}
+	ciphertext, err := other.StateEncryption().EncryptState(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptState failed: %s", err)
+	}
+
+	_, err = enc.StateEncryption().DecryptState(ciphertext)
+	if !errors.Is(err, ErrNoKeyProviderConfigured) {
+		t.Fatalf("expected ErrNoKeyProviderConfigured, got %v", err)
+	}
+}
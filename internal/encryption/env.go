@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ConfigEnvVar is a fallback source for encryption configuration, read
+// before the `encryption {}` block in configuration can be parsed (state
+// and plan files need to be decryptable before Terraform can even load the
+// backend that would normally supply configuration). Most users configure
+// encryption via the HCL block; this exists for bootstrapping cases like
+// `terraform show` on a bare file path with no working directory.
+const ConfigEnvVar = "TF_ENCRYPTION"
+
+// envConfig mirrors Config/TargetConfig but with exported JSON tags, since
+// Config itself is shaped for decoding out of HCL rather than JSON.
+type envConfig struct {
+	State *envTargetConfig `json:"state"`
+	Plan  *envTargetConfig `json:"plan"`
+}
+
+type envTargetConfig struct {
+	Method            string            `json:"method"`
+	KeyProvider       string            `json:"key_provider"`
+	KeyProviderConfig map[string]string `json:"key_provider_config"`
+}
+
+// ConfigFromEnv reads and decodes the TF_ENCRYPTION environment variable, if
+// set. It returns a zero Config (fully unencrypted) if the variable is
+// unset.
+func ConfigFromEnv() (Config, error) {
+	raw := os.Getenv(ConfigEnvVar)
+	if raw == "" {
+		return Config{}, nil
+	}
+
+	var decoded envConfig
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return Config{}, fmt.Errorf("invalid %s: %w", ConfigEnvVar, err)
+	}
+
+	return Config{
+		State: decoded.State.toTargetConfig(),
+		Plan:  decoded.Plan.toTargetConfig(),
+	}, nil
+}
+
+func (e *envTargetConfig) toTargetConfig() *TargetConfig {
+	if e == nil {
+		return nil
+	}
+	return &TargetConfig{
+		Method:            e.Method,
+		KeyProvider:       e.KeyProvider,
+		KeyProviderConfig: e.KeyProviderConfig,
+	}
+}
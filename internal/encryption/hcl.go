@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// rootSchema is decoded against each root module file in isolation: all we
+// need out of it, this early, is whichever single `encryption {}` block is
+// present. Everything else in the file is left alone for the normal configs
+// package to parse later.
+type rootSchema struct {
+	Encryption *Config  `hcl:"encryption,block"`
+	Remain     hcl.Body `hcl:",remain"`
+}
+
+// LoadConfigDir scans dir for a root module's `encryption {}` block and
+// decodes it, independently of (and before) the rest of configuration
+// loading -- state and plan files have to be decryptable before Terraform
+// can get far enough to load a backend, which is what would otherwise parse
+// this block as part of the normal configs.Config tree.
+//
+// It is not an error for dir to contain no `encryption {}` block at all; a
+// zero Config (fully unencrypted) is returned in that case. It is an error
+// for more than one `.tf`/`.tf.json` file in dir to define one.
+func LoadConfigDir(dir string) (Config, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// A missing or unreadable directory isn't fatal here: show, in
+		// particular, may be pointed at a bare file with no working
+		// directory to speak of, and has the TF_ENCRYPTION variable as a
+		// fallback for that case.
+		return Config{}, diags
+	}
+
+	parser := hclparse.NewParser()
+	var cfg Config
+	var definedIn string
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		var f *hcl.File
+		var fileDiags hcl.Diagnostics
+		path := filepath.Join(dir, name)
+		switch {
+		case strings.HasSuffix(name, ".tf"):
+			f, fileDiags = parser.ParseHCLFile(path)
+		case strings.HasSuffix(name, ".tf.json"):
+			f, fileDiags = parser.ParseJSONFile(path)
+		default:
+			continue
+		}
+		diags = append(diags, fileDiags...)
+		if fileDiags.HasErrors() {
+			continue
+		}
+
+		var root rootSchema
+		if declDiags := gohcl.DecodeBody(f.Body, nil, &root); declDiags.HasErrors() {
+			diags = append(diags, declDiags...)
+			continue
+		}
+		if root.Encryption == nil {
+			continue
+		}
+		if definedIn != "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate encryption block",
+				Detail:   fmt.Sprintf("An encryption block was already defined in %s; only one is allowed per configuration.", definedIn),
+			})
+			continue
+		}
+		definedIn = path
+		cfg = *root.Encryption
+	}
+
+	return cfg, diags
+}
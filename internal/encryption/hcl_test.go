@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigDir(t *testing.T) {
+	t.Run("no encryption block", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "main.tf", `resource "null_resource" "example" {}`)
+
+		cfg, diags := LoadConfigDir(dir)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+		if cfg.State != nil || cfg.Plan != nil {
+			t.Fatalf("expected a zero Config, got %#v", cfg)
+		}
+	})
+
+	t.Run("encryption block", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "encryption.tf", `
+encryption {
+  state {
+    method       = "aes_gcm"
+    key_provider = "pbkdf2"
+    key_provider_config = {
+      passphrase = "correct horse battery staple"
+    }
+  }
+}
+`)
+
+		cfg, diags := LoadConfigDir(dir)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+		if cfg.State == nil {
+			t.Fatal("expected a non-nil State target config")
+		}
+		if cfg.State.Method != "aes_gcm" || cfg.State.KeyProvider != "pbkdf2" {
+			t.Fatalf("unexpected state target config: %#v", cfg.State)
+		}
+		if cfg.State.KeyProviderConfig["passphrase"] != "correct horse battery staple" {
+			t.Fatalf("unexpected key provider config: %#v", cfg.State.KeyProviderConfig)
+		}
+	})
+
+	t.Run("encryption block in a .tf.json root module", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "encryption.tf.json", `{
+  "encryption": {
+    "state": {
+      "method": "aes_gcm",
+      "key_provider": "static",
+      "key_provider_config": {
+        "key": "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"
+      }
+    }
+  }
+}`)
+
+		cfg, diags := LoadConfigDir(dir)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+		if cfg.State == nil {
+			t.Fatal("expected a non-nil State target config")
+		}
+		if cfg.State.Method != "aes_gcm" || cfg.State.KeyProvider != "static" {
+			t.Fatalf("unexpected state target config: %#v", cfg.State)
+		}
+	})
+
+	t.Run("duplicate encryption blocks", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "a.tf", `
+encryption {
+  state {
+    method       = "aes_gcm"
+    key_provider = "static"
+  }
+}
+`)
+		writeFile(t, dir, "b.tf", `
+encryption {
+  plan {
+    method       = "aes_gcm"
+    key_provider = "static"
+  }
+}
+`)
+
+		_, diags := LoadConfigDir(dir)
+		if !diags.HasErrors() {
+			t.Fatal("expected an error for duplicate encryption blocks")
+		}
+	})
+
+	t.Run("nonexistent directory", func(t *testing.T) {
+		cfg, diags := LoadConfigDir(filepath.Join(t.TempDir(), "does-not-exist"))
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+		if cfg.State != nil || cfg.Plan != nil {
+			t.Fatalf("expected a zero Config, got %#v", cfg)
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	envOnly := Config{State: &TargetConfig{Method: "aes_gcm", KeyProvider: "static"}}
+	hclOnly := Config{Plan: &TargetConfig{Method: "aes_gcm", KeyProvider: "pbkdf2"}}
+
+	merged := Merge(envOnly, hclOnly)
+	if merged.State != envOnly.State {
+		t.Fatal("expected primary's State to win")
+	}
+	if merged.Plan != hclOnly.Plan {
+		t.Fatal("expected fallback's Plan to fill in where primary had none")
+	}
+
+	bothSet := Config{State: &TargetConfig{Method: "aes_gcm", KeyProvider: "static"}}
+	fallbackState := Config{State: &TargetConfig{Method: "aes_gcm", KeyProvider: "pbkdf2"}}
+	merged = Merge(bothSet, fallbackState)
+	if merged.State != bothSet.State {
+		t.Fatal("expected primary's State to take precedence over fallback's")
+	}
+}
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %s", name, err)
+	}
+}
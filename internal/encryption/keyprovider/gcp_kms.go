@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// This file pulls in cloud.google.com/go/kms/... as a new direct
+// dependency; go.mod/go.sum need `go mod tidy` run against them once this
+// tree has a module manifest to update, which it does not today.
+package keyprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	kmspb "cloud.google.com/go/kms/apiv1/kmspb"
+)
+
+func init() {
+	Register("gcp_kms", newGCPKMSKeyProvider)
+}
+
+const gcpKMSDataKeyLength = 32 // AES-256
+
+// gcpKMSKeyProvider generates a random local data key and asks Cloud KMS to
+// encrypt ("wrap") it with the configured key, mirroring the aws_kms
+// provider's envelope-encryption approach. Cloud KMS's symmetric keys don't
+// have a GenerateDataKey equivalent, so the data key is generated locally
+// and only the wrap/unwrap step is delegated to KMS.
+type gcpKMSKeyProvider struct {
+	client  *kms.KeyManagementClient
+	keyName string
+	dataKey []byte
+	wrapped []byte
+}
+
+func newGCPKMSKeyProvider(config map[string]string) (KeyProvider, error) {
+	keyName, ok := config["kms_key_name"]
+	if !ok || keyName == "" {
+		return nil, fmt.Errorf("gcp_kms key provider requires a non-empty \"kms_key_name\" argument (projects/*/locations/*/keyRings/*/cryptoKeys/*)")
+	}
+
+	ctx := context.Background()
+	client, err := kms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GCP KMS client: %w", err)
+	}
+
+	return &gcpKMSKeyProvider{client: client, keyName: keyName}, nil
+}
+
+func (p *gcpKMSKeyProvider) Key(ctx context.Context) ([]byte, error) {
+	dataKey := make([]byte, gcpKMSDataKeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	resp, err := p.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      p.keyName,
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GCP KMS Encrypt failed: %w", err)
+	}
+
+	p.dataKey = dataKey
+	p.wrapped = resp.Ciphertext
+	return dataKey, nil
+}
+
+func (p *gcpKMSKeyProvider) KeyForDecryption(ctx context.Context, metadata map[string]string) ([]byte, error) {
+	wrappedB64, ok := metadata["wrapped_key"]
+	if !ok {
+		return nil, fmt.Errorf("encrypted payload is missing the KMS-wrapped key needed to decrypt it")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedB64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped key: %w", err)
+	}
+
+	resp, err := p.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       p.keyName,
+		Ciphertext: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GCP KMS Decrypt failed: %w", err)
+	}
+	return resp.Plaintext, nil
+}
+
+func (p *gcpKMSKeyProvider) Metadata() map[string]string {
+	if p.wrapped == nil {
+		return nil
+	}
+	return map[string]string{"wrapped_key": base64.StdEncoding.EncodeToString(p.wrapped)}
+}
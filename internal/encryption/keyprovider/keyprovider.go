@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package keyprovider defines the KeyProvider interface implemented by each
+// supported `key_provider` in an `encryption {}` block, along with the
+// built-in providers: pbkdf2 (passphrase), static (raw hex key), aws_kms,
+// and gcp_kms.
+package keyprovider
+
+import "context"
+
+// KeyProvider produces the raw symmetric key material that a Method uses to
+// encrypt or decrypt state and plan data. Implementations may derive the key
+// locally (pbkdf2, static) or fetch it from an external service (aws_kms,
+// gcp_kms); either way the returned key never leaves process memory longer
+// than necessary.
+type KeyProvider interface {
+	// Key returns the raw key bytes to use for encryption. Decryption uses
+	// KeyForDecryption instead, which receives the envelope's metadata (such
+	// as the pbkdf2 salt) needed to reproduce the same key.
+	Key(ctx context.Context) ([]byte, error)
+
+	// KeyForDecryption re-derives (or re-fetches) the key used to produce an
+	// envelope whose provider-specific metadata (e.g. a pbkdf2 salt) is
+	// passed in as metadata.
+	KeyForDecryption(ctx context.Context, metadata map[string]string) ([]byte, error)
+
+	// Metadata returns provider-specific values that must travel alongside
+	// the ciphertext in order to reproduce the same key later (e.g. the
+	// random salt a passphrase was combined with). Providers with nothing to
+	// store, like static, return nil; the KMS providers return the
+	// KMS-wrapped data key from their most recent Key call.
+	Metadata() map[string]string
+}
+
+// Factory constructs a KeyProvider from its HCL-decoded configuration
+// arguments, which arrive as a generic string map because the encryption
+// block is parsed before the rest of the configuration (and so can't use
+// the usual schema/cty decoding path that depends on provider schemas).
+type Factory func(config map[string]string) (KeyProvider, error)
+
+var factories = map[string]Factory{}
+
+// Register adds a key provider factory under the given name, for use in
+// `key_provider = "<name>"` inside an `encryption {}` block. It panics on a
+// duplicate name, the same as other init-time registries in this codebase
+// (e.g. provisioners and backends).
+func Register(name string, factory Factory) {
+	if _, exists := factories[name]; exists {
+		panic("keyprovider: duplicate registration for " + name)
+	}
+	factories[name] = factory
+}
+
+// New looks up the registered factory for name and uses it to build a
+// KeyProvider from config.
+func New(name string, config map[string]string) (KeyProvider, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, UnknownProviderError{Name: name}
+	}
+	return factory(config)
+}
+
+// UnknownProviderError is returned by New when no key provider is
+// registered under the requested name, so callers can tell an unknown name
+// apart from an error in a known provider's own configuration.
+type UnknownProviderError struct {
+	Name string
+}
+
+func (e UnknownProviderError) Error() string {
+	return "unknown key provider \"" + e.Name + "\""
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func init() {
+	Register("pbkdf2", newPBKDF2KeyProvider)
+}
+
+const (
+	pbkdf2Iterations = 600000 // OWASP 2023 recommendation for PBKDF2-HMAC-SHA256
+	pbkdf2KeyLength  = 32     // AES-256
+	pbkdf2SaltLength = 16
+)
+
+// pbkdf2KeyProvider derives a key from a user-supplied passphrase using
+// PBKDF2-HMAC-SHA256, combined with a random salt that's generated once when
+// the provider is constructed (so every encryption by one provider uses the
+// same salt) and stored in the envelope metadata so the same key can be
+// re-derived on decrypt.
+type pbkdf2KeyProvider struct {
+	passphrase string
+	salt       []byte
+}
+
+func newPBKDF2KeyProvider(config map[string]string) (KeyProvider, error) {
+	passphrase, ok := config["passphrase"]
+	if !ok || passphrase == "" {
+		return nil, fmt.Errorf("pbkdf2 key provider requires a non-empty \"passphrase\" argument")
+	}
+
+	salt := make([]byte, pbkdf2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return &pbkdf2KeyProvider{passphrase: passphrase, salt: salt}, nil
+}
+
+func (p *pbkdf2KeyProvider) Key(ctx context.Context) ([]byte, error) {
+	return pbkdf2.Key([]byte(p.passphrase), p.salt, pbkdf2Iterations, pbkdf2KeyLength, sha256.New), nil
+}
+
+func (p *pbkdf2KeyProvider) KeyForDecryption(ctx context.Context, metadata map[string]string) ([]byte, error) {
+	saltHex, ok := metadata["salt"]
+	if !ok {
+		return nil, fmt.Errorf("encrypted payload is missing the pbkdf2 salt needed to derive its key")
+	}
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pbkdf2 salt: %w", err)
+	}
+	return pbkdf2.Key([]byte(p.passphrase), salt, pbkdf2Iterations, pbkdf2KeyLength, sha256.New), nil
+}
+
+func (p *pbkdf2KeyProvider) Metadata() map[string]string {
+	return map[string]string{"salt": hex.EncodeToString(p.salt)}
+}
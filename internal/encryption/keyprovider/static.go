@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+)
+
+func init() {
+	Register("static", newStaticKeyProvider)
+}
+
+// staticKeyProvider uses an operator-supplied hex-encoded key directly. It's
+// the simplest provider and has no per-encryption metadata: the same key
+// string always decodes to the same bytes.
+type staticKeyProvider struct {
+	key []byte
+}
+
+func newStaticKeyProvider(config map[string]string) (KeyProvider, error) {
+	keyHex, ok := config["key"]
+	if !ok || keyHex == "" {
+		return nil, fmt.Errorf("static key provider requires a non-empty \"key\" argument (hex-encoded)")
+	}
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("static key provider \"key\" argument must be hex-encoded: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		// valid AES-128/192/256 key lengths
+	default:
+		return nil, fmt.Errorf("static key provider \"key\" must decode to 16, 24, or 32 bytes, got %d", len(key))
+	}
+	return &staticKeyProvider{key: key}, nil
+}
+
+func (p *staticKeyProvider) Key(ctx context.Context) ([]byte, error) {
+	return p.key, nil
+}
+
+func (p *staticKeyProvider) KeyForDecryption(ctx context.Context, metadata map[string]string) ([]byte, error) {
+	return p.key, nil
+}
+
+func (p *staticKeyProvider) Metadata() map[string]string {
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package method
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/hashicorp/terraform/internal/encryption/keyprovider"
+)
+
+func init() {
+	Register("aes_gcm", newAESGCMMethod)
+}
+
+const aesGCMNonceLength = 12
+
+type aesGCMMethod struct{}
+
+func newAESGCMMethod(config map[string]string) (Method, error) {
+	// aes_gcm takes no arguments of its own; everything it needs comes from
+	// the configured key provider.
+	return aesGCMMethod{}, nil
+}
+
+func (aesGCMMethod) Encrypt(plaintext []byte, kp keyprovider.KeyProvider) (Envelope, error) {
+	key, err := kp.Key(context.Background())
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to obtain encryption key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	nonce := make([]byte, aesGCMNonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return Envelope{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	return Envelope{
+		Method:      "aes_gcm",
+		KeyMetadata: kp.Metadata(),
+		Nonce:       nonce,
+		Ciphertext:  ciphertext,
+	}, nil
+}
+
+func (aesGCMMethod) Decrypt(envelope Envelope, kp keyprovider.KeyProvider) ([]byte, error) {
+	key, err := kp.KeyForDecryption(context.Background(), envelope.KeyMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain decryption key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Nonce) != aesGCMNonceLength {
+		return nil, fmt.Errorf("invalid nonce length %d", len(envelope.Nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed, wrong key or corrupted data: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AES key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
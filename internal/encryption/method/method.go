@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package method defines the Method interface implemented by each supported
+// `method` in an `encryption {}` block -- the envelope format and cipher
+// that turns a KeyProvider's key into ciphertext and back. The only
+// built-in method today is aes_gcm.
+package method
+
+import "github.com/hashicorp/terraform/internal/encryption/keyprovider"
+
+// Method turns plaintext state/plan bytes into an Envelope, and back, using
+// a key obtained from a keyprovider.KeyProvider.
+type Method interface {
+	Encrypt(plaintext []byte, key keyprovider.KeyProvider) (Envelope, error)
+	Decrypt(envelope Envelope, key keyprovider.KeyProvider) ([]byte, error)
+}
+
+// Envelope is the decoded form of the on-disk JSON wrapper around encrypted
+// state/plan data:
+//
+//	{
+//	  "encrypted": true,
+//	  "key_provider_id": "pbkdf2",
+//	  "method": "aes_gcm",
+//	  "key_metadata": {"salt": "..."},
+//	  "nonce": "...",
+//	  "ciphertext": "..."
+//	}
+type Envelope struct {
+	KeyProviderID string            `json:"key_provider_id"`
+	Method        string            `json:"method"`
+	KeyMetadata   map[string]string `json:"key_metadata,omitempty"`
+	Nonce         []byte            `json:"nonce"`
+	Ciphertext    []byte            `json:"ciphertext"`
+}
+
+// Factory constructs a Method from its HCL-decoded configuration arguments.
+type Factory func(config map[string]string) (Method, error)
+
+var factories = map[string]Factory{}
+
+// Register adds a method factory under the given name, for use in
+// `method = "<name>"` inside an `encryption {}` block.
+func Register(name string, factory Factory) {
+	if _, exists := factories[name]; exists {
+		panic("method: duplicate registration for " + name)
+	}
+	factories[name] = factory
+}
+
+// New looks up the registered factory for name and uses it to build a
+// Method from config.
+func New(name string, config map[string]string) (Method, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, UnknownMethodError{Name: name}
+	}
+	return factory(config)
+}
+
+// UnknownMethodError is returned by New when no method is registered under
+// the requested name.
+type UnknownMethodError struct {
+	Name string
+}
+
+func (e UnknownMethodError) Error() string {
+	return "unknown encryption method \"" + e.Name + "\""
+}
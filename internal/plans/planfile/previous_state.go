@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/internal/states/statefile"
+)
+
+// readStateFile reads the zip member called name out of r's underlying
+// archive and parses it as a state file. It returns a nil file and a nil
+// error if no member by that name exists, since not every entry this
+// package knows how to read (tfstate-prev in particular) is present in
+// every plan file.
+func (r *Reader) readStateFile(name string) (*statefile.File, error) {
+	for _, zf := range r.zip.File {
+		if zf.Name != name {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return statefile.Read(rc)
+	}
+	return nil, nil
+}
+
+// tfstatePreviousFilename is the name of the zip entry that stores the
+// state snapshot Terraform used as the basis for planning, before applying
+// any of the plan's changes. It sits alongside tfstateFilename (the
+// "planned" snapshot ReadStateFile returns), which additionally reflects
+// input variables and any out-of-band refresh Terraform performed while
+// producing the plan.
+const tfstatePreviousFilename = "tfstate-prev"
+
+// ReadPreviousStateFile reads the prior state snapshot embedded in the plan
+// file: what Terraform believed existed before planning, as opposed to the
+// post-refresh, pre-apply snapshot that ReadStateFile returns. Not every
+// plan file has one -- in particular, a plan for a brand new empty state
+// has nothing to record here -- so a nil result with no error is valid.
+func (r *Reader) ReadPreviousStateFile() (*statefile.File, error) {
+	snap, err := r.readStateFile(tfstatePreviousFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prior state snapshot from plan file: %w", err)
+	}
+	return snap, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+)
+
+// readerWithZipMembers builds a *Reader around an in-memory zip archive
+// containing the given members, without going through OpenWrapped -- this
+// lets readStateFile/ReadPreviousStateFile be tested directly against a
+// known archive layout.
+func readerWithZipMembers(t *testing.T, members map[string][]byte) *Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, contents := range members {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip member %s: %s", name, err)
+		}
+		if _, err := w.Write(contents); err != nil {
+			t.Fatalf("failed to write zip member %s: %s", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %s", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open zip reader: %s", err)
+	}
+	return &Reader{zip: zr}
+}
+
+func encodedStateFile(t *testing.T, lineage string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	f := &statefile.File{Lineage: lineage, Serial: 1, State: states.NewState()}
+	if err := statefile.Write(f, &buf); err != nil {
+		t.Fatalf("failed to write state file: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadPreviousStateFile(t *testing.T) {
+	t.Run("present", func(t *testing.T) {
+		r := readerWithZipMembers(t, map[string][]byte{
+			tfstatePreviousFilename: encodedStateFile(t, "prior-snapshot"),
+		})
+
+		got, err := r.ReadPreviousStateFile()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got == nil {
+			t.Fatal("expected a non-nil state file")
+		}
+		if got.Lineage != "prior-snapshot" {
+			t.Fatalf("unexpected lineage: got %q", got.Lineage)
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		r := readerWithZipMembers(t, map[string][]byte{
+			"tfstate": encodedStateFile(t, "planned-snapshot"),
+		})
+
+		got, err := r.ReadPreviousStateFile()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != nil {
+			t.Fatalf("expected a nil state file when tfstate-prev is absent, got %#v", got)
+		}
+	})
+}
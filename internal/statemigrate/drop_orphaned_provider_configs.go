@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemigrate
+
+import (
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+func init() {
+	Register("drop-orphaned-provider-configs", dropOrphanedProviderConfigs)
+}
+
+// dropOrphanedProviderConfigs removes resource instances left over in state
+// that are configured against a provider alias no longer declared anywhere
+// in config -- typically because the `provider "aws" { alias = "..." }`
+// block (and the resources that used it) were removed from config, but the
+// resources were never destroyed first. Rendering them against a provider
+// config that no longer exists produces a confusing or broken show output,
+// so they're dropped from the migrated view entirely.
+//
+// This only ever applies to *aliased* provider configs: a resource using
+// the default (unaliased) provider config for its type is left alone even
+// if config happens not to declare an explicit `provider` block for it,
+// since the default config is implicit and doesn't require one. Without a
+// config to compare against (config == nil, e.g. showing a bare state file
+// with no working directory) this migration is a no-op, since "orphaned"
+// is undefined without something to be orphaned from.
+func dropOrphanedProviderConfigs(state *states.State, config *configs.Config) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if config == nil || config.Module == nil {
+		return state, diags
+	}
+
+	// declaredAliases is keyed on the alias name together with the full
+	// provider address it resolves to (not just the provider's type), so
+	// that a resource whose alias still exists in config but now points at
+	// a different provider (e.g. after a fork or a source move that
+	// collides on type name) is still treated as orphaned rather than
+	// silently kept pointed at a provider config that no longer resolves to
+	// the same provider. A provider block only carries its local name, so
+	// resolving it to the addrs.Provider it implies goes through the same
+	// provider-requirements lookup the rest of the config loader uses. The
+	// key also carries the path of the module declaring the provider block,
+	// since that is the module a resource's AbsProviderConfig records.
+	declaredAliases := make(map[string]bool)
+	config.DeepEach(func(c *configs.Config) {
+		if c.Module == nil {
+			return
+		}
+		for _, pc := range c.Module.ProviderConfigs {
+			if pc.Alias == "" {
+				continue
+			}
+			resolved := c.Module.ProviderForLocalConfig(addrs.LocalProviderConfig{LocalName: pc.Name, Alias: pc.Alias})
+			declaredAliases[c.Path.String()+"|"+pc.Alias+"/"+resolved.String()] = true
+		}
+	})
+
+	isOrphaned := func(pc addrs.AbsProviderConfig) bool {
+		if pc.Alias == "" {
+			return false
+		}
+		return !declaredAliases[pc.Module.String()+"|"+pc.Alias+"/"+pc.Provider.String()]
+	}
+
+	changed := false
+	for _, module := range state.Modules {
+		for _, resource := range module.Resources {
+			if isOrphaned(resource.ProviderConfig) {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return state, diags
+	}
+
+	newState := state.DeepCopy()
+	for _, module := range newState.Modules {
+		for name, resource := range module.Resources {
+			if isOrphaned(resource.ProviderConfig) {
+				delete(module.Resources, name)
+			}
+		}
+	}
+	return newState, diags
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemigrate
+
+import (
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+func init() {
+	Register("legacy-provider-namespace", rewriteLegacyProviderNamespace)
+}
+
+// legacyProviderNamespaces maps provider type names that predate the
+// registry-based namespace/type addressing scheme onto the namespace they
+// were moved into when they joined the registry, so that state recorded
+// before that move still resolves to the right provider.
+var legacyProviderNamespaces = map[string]string{
+	"template": "hashicorp",
+	"null":     "hashicorp",
+}
+
+// rewriteLegacyProviderNamespace rewrites resource instances whose provider
+// address still uses the pre-0.13 unqualified form (namespace "-") to the
+// namespace those providers were later published under, so that `terraform
+// show` renders legacy state using the same provider address Terraform
+// would resolve today.
+func rewriteLegacyProviderNamespace(state *states.State, config *configs.Config) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	changed := false
+	for _, module := range state.Modules {
+		for _, resource := range module.Resources {
+			if _, ok := legacyNamespaceFor(resource.ProviderConfig.Provider); ok {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return state, diags
+	}
+
+	newState := state.DeepCopy()
+	for _, module := range newState.Modules {
+		for _, resource := range module.Resources {
+			if namespace, ok := legacyNamespaceFor(resource.ProviderConfig.Provider); ok {
+				resource.ProviderConfig.Provider.Namespace = namespace
+				resource.ProviderConfig.Provider.Hostname = addrs.DefaultProviderRegistryHost
+			}
+		}
+	}
+	return newState, diags
+}
+
+func legacyNamespaceFor(provider addrs.Provider) (string, bool) {
+	if provider.Namespace != addrs.LegacyProviderNamespace {
+		return "", false
+	}
+	namespace, ok := legacyProviderNamespaces[provider.Type]
+	return namespace, ok
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemigrate
+
+import (
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+func init() {
+	Register("provider-source-move", rewriteMovedProviderSource)
+}
+
+// movedProviderSources maps a provider's old registry address onto the new
+// one it moved to when its publisher changed, distinct from
+// legacyProviderNamespaces (which only handles providers that predate
+// registry addressing at all). Each entry here is a provider that was
+// already registry-addressed but later moved to a different
+// namespace/type/hostname entirely -- e.g. a provider that was donated from
+// a community namespace to its current maintainer.
+var movedProviderSources = map[addrs.Provider]addrs.Provider{
+	{Hostname: addrs.DefaultProviderRegistryHost, Namespace: "terraform-providers", Type: "google"}: {
+		Hostname: addrs.DefaultProviderRegistryHost, Namespace: "hashicorp", Type: "google",
+	},
+	{Hostname: addrs.DefaultProviderRegistryHost, Namespace: "terraform-providers", Type: "aws"}: {
+		Hostname: addrs.DefaultProviderRegistryHost, Namespace: "hashicorp", Type: "aws",
+	},
+}
+
+// rewriteMovedProviderSource rewrites resource instances whose provider
+// address matches one of movedProviderSources, so that `terraform show`
+// renders a resource under the provider address Terraform would resolve
+// today rather than the one it was originally created with.
+func rewriteMovedProviderSource(state *states.State, config *configs.Config) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	changed := false
+	for _, module := range state.Modules {
+		for _, resource := range module.Resources {
+			if _, ok := movedProviderSources[resource.ProviderConfig.Provider]; ok {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return state, diags
+	}
+
+	newState := state.DeepCopy()
+	for _, module := range newState.Modules {
+		for _, resource := range module.Resources {
+			if moved, ok := movedProviderSources[resource.ProviderConfig.Provider]; ok {
+				resource.ProviderConfig.Provider = moved
+			}
+		}
+	}
+	return newState, diags
+}
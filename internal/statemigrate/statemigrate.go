@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package statemigrate applies small, pure rewrites to an in-memory
+// states.State so that commands like `terraform show` can render a state
+// snapshot that predates some since-changed convention (a provider that
+// moved registry namespaces, a resource whose provider source address
+// changed) without requiring the operator to run a separate upgrade step
+// first.
+//
+// Migrations registered here must never write to the backend themselves:
+// show applies them to produce a migrated *view* of the loaded state while
+// leaving the persisted copy untouched. A future `terraform state migrate`
+// command can run the same registry and persist the result.
+package statemigrate
+
+import (
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// MigrationFunc rewrites state, given the configuration it's being loaded
+// alongside (some migrations, like dropping orphaned provider configs, need
+// to know what the configuration still references). It must not mutate its
+// input in place; return the same *states.State back if there's nothing to
+// do.
+type MigrationFunc func(state *states.State, config *configs.Config) (*states.State, tfdiags.Diagnostics)
+
+type registeredMigration struct {
+	id string
+	fn MigrationFunc
+}
+
+var migrations []registeredMigration
+
+// Register adds a migration under the given id, to be applied by Apply in
+// registration order. Call it from an init() function in the file that
+// defines the migration, the same way providers and provisioners register
+// themselves elsewhere in this codebase.
+func Register(id string, fn MigrationFunc) {
+	for _, m := range migrations {
+		if m.id == id {
+			panic("statemigrate: duplicate registration for " + id)
+		}
+	}
+	migrations = append(migrations, registeredMigration{id: id, fn: fn})
+}
+
+// Apply runs every registered migration against state, in registration
+// order, and returns the migrated state along with the ids of whichever
+// migrations actually changed something. A caller that wants to detect
+// drift between the stored and migrated state (e.g. to surface it in JSON
+// output) can check whether the returned id slice is empty.
+func Apply(state *states.State, config *configs.Config) (*states.State, []string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var fired []string
+
+	if state == nil {
+		return state, fired, diags
+	}
+
+	current := state
+	for _, m := range migrations {
+		next, migDiags := m.fn(current, config)
+		diags = diags.Append(migDiags)
+		if migDiags.HasErrors() {
+			return current, fired, diags
+		}
+		if next != current {
+			fired = append(fired, m.id)
+			current = next
+		}
+	}
+	return current, fired, diags
+}
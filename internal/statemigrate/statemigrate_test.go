@@ -0,0 +1,249 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemigrate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+func newTestState(resources map[string]*states.Resource) *states.State {
+	return &states.State{
+		Modules: map[string]*states.Module{
+			"": {Resources: resources},
+		},
+	}
+}
+
+func TestApply_legacyProviderNamespace(t *testing.T) {
+	state := newTestState(map[string]*states.Resource{
+		"null_resource.example": {
+			ProviderConfig: addrs.AbsProviderConfig{
+				Provider: addrs.Provider{Namespace: addrs.LegacyProviderNamespace, Type: "null"},
+			},
+		},
+	})
+
+	migrated, fired, diags := Apply(state, nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+
+	found := false
+	for _, id := range fired {
+		if id == "legacy-provider-namespace" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected legacy-provider-namespace to fire, got %v", fired)
+	}
+
+	got := migrated.Modules[""].Resources["null_resource.example"].ProviderConfig.Provider
+	if got.Namespace != "hashicorp" || got.Hostname != addrs.DefaultProviderRegistryHost {
+		t.Fatalf("unexpected provider after migration: %#v", got)
+	}
+
+	// The original state must be untouched.
+	orig := state.Modules[""].Resources["null_resource.example"].ProviderConfig.Provider
+	if orig.Namespace != addrs.LegacyProviderNamespace {
+		t.Fatalf("expected original state to be left alone, got %#v", orig)
+	}
+}
+
+func TestApply_providerSourceMove(t *testing.T) {
+	state := newTestState(map[string]*states.Resource{
+		"google_compute_instance.example": {
+			ProviderConfig: addrs.AbsProviderConfig{
+				Provider: addrs.Provider{Hostname: addrs.DefaultProviderRegistryHost, Namespace: "terraform-providers", Type: "google"},
+			},
+		},
+	})
+
+	migrated, fired, diags := Apply(state, nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+
+	found := false
+	for _, id := range fired {
+		if id == "provider-source-move" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected provider-source-move to fire, got %v", fired)
+	}
+
+	got := migrated.Modules[""].Resources["google_compute_instance.example"].ProviderConfig.Provider
+	if got.Namespace != "hashicorp" {
+		t.Fatalf("unexpected provider after migration: %#v", got)
+	}
+}
+
+// moduleWithAlias builds a *configs.Module declaring a single aliased
+// provider block, with provider in its required_providers so that the
+// block's local name resolves to provider via ProviderForLocalConfig --
+// mirroring how a real parsed module relates the two.
+func moduleWithAlias(localName, alias string, provider addrs.Provider) *configs.Module {
+	return &configs.Module{
+		ProviderConfigs: map[string]*configs.Provider{
+			localName + "." + alias: {Name: localName, Alias: alias},
+		},
+		ProviderRequirements: &configs.RequiredProviders{
+			RequiredProviders: map[string]*configs.RequiredProvider{
+				localName: {Type: provider},
+			},
+		},
+	}
+}
+
+func TestApply_dropOrphanedProviderConfigs(t *testing.T) {
+	awsEast := addrs.Provider{Hostname: addrs.DefaultProviderRegistryHost, Namespace: "hashicorp", Type: "aws"}
+
+	t.Run("alias no longer declared is dropped", func(t *testing.T) {
+		state := newTestState(map[string]*states.Resource{
+			"aws_instance.example": {
+				ProviderConfig: addrs.AbsProviderConfig{Provider: awsEast, Alias: "east"},
+			},
+		})
+		config := &configs.Config{Module: &configs.Module{ProviderConfigs: map[string]*configs.Provider{}}}
+
+		migrated, fired, diags := Apply(state, config)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+
+		found := false
+		for _, id := range fired {
+			if id == "drop-orphaned-provider-configs" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected drop-orphaned-provider-configs to fire, got %v", fired)
+		}
+		if _, ok := migrated.Modules[""].Resources["aws_instance.example"]; ok {
+			t.Fatal("expected the orphaned resource to be dropped from the migrated state")
+		}
+		if _, ok := state.Modules[""].Resources["aws_instance.example"]; !ok {
+			t.Fatal("expected the original state to be left alone")
+		}
+	})
+
+	t.Run("alias still declared against the same provider is kept", func(t *testing.T) {
+		state := newTestState(map[string]*states.Resource{
+			"aws_instance.example": {
+				ProviderConfig: addrs.AbsProviderConfig{Provider: awsEast, Alias: "east"},
+			},
+		})
+		config := &configs.Config{Module: moduleWithAlias("aws", "east", awsEast)}
+
+		migrated, fired, diags := Apply(state, config)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+		for _, id := range fired {
+			if id == "drop-orphaned-provider-configs" {
+				t.Fatal("expected drop-orphaned-provider-configs not to fire")
+			}
+		}
+		if _, ok := migrated.Modules[""].Resources["aws_instance.example"]; !ok {
+			t.Fatal("expected the resource to be kept")
+		}
+	})
+
+	t.Run("alias declared but now against a different provider is still dropped", func(t *testing.T) {
+		state := newTestState(map[string]*states.Resource{
+			"aws_instance.example": {
+				ProviderConfig: addrs.AbsProviderConfig{Provider: awsEast, Alias: "east"},
+			},
+		})
+		forked := addrs.Provider{Hostname: addrs.DefaultProviderRegistryHost, Namespace: "someoneelse", Type: "aws"}
+		config := &configs.Config{Module: moduleWithAlias("aws", "east", forked)}
+
+		migrated, fired, diags := Apply(state, config)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+		found := false
+		for _, id := range fired {
+			if id == "drop-orphaned-provider-configs" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected drop-orphaned-provider-configs to fire when the alias moved to a different provider, got %v", fired)
+		}
+		if _, ok := migrated.Modules[""].Resources["aws_instance.example"]; ok {
+			t.Fatal("expected the resource to be dropped since its alias now resolves to a different provider")
+		}
+	})
+
+	t.Run("unaliased provider config is never orphaned", func(t *testing.T) {
+		state := newTestState(map[string]*states.Resource{
+			"aws_instance.example": {
+				ProviderConfig: addrs.AbsProviderConfig{Provider: awsEast},
+			},
+		})
+		config := &configs.Config{Module: &configs.Module{ProviderConfigs: map[string]*configs.Provider{}}}
+
+		migrated, _, diags := Apply(state, config)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+		if _, ok := migrated.Modules[""].Resources["aws_instance.example"]; !ok {
+			t.Fatal("expected the unaliased resource to be kept regardless of config")
+		}
+	})
+}
+
+func TestApply_noMigrationsNeeded(t *testing.T) {
+	state := newTestState(map[string]*states.Resource{
+		"null_resource.example": {
+			ProviderConfig: addrs.AbsProviderConfig{
+				Provider: addrs.Provider{Hostname: addrs.DefaultProviderRegistryHost, Namespace: "hashicorp", Type: "null"},
+			},
+		},
+	})
+
+	migrated, fired, diags := Apply(state, nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+	if len(fired) != 0 {
+		t.Fatalf("expected no migrations to fire, got %v", fired)
+	}
+	if migrated != state {
+		t.Fatal("expected Apply to return the same *states.State when nothing changed")
+	}
+}
+
+func TestApply_nilState(t *testing.T) {
+	migrated, fired, diags := Apply(nil, nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err())
+	}
+	if migrated != nil {
+		t.Fatal("expected a nil state to pass through unchanged")
+	}
+	if len(fired) != 0 {
+		t.Fatalf("expected no migrations to fire for a nil state, got %v", fired)
+	}
+}
+
+func TestRegister_duplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic registering a duplicate migration id")
+		}
+	}()
+	Register("legacy-provider-namespace", func(state *states.State, _ *configs.Config) (*states.State, tfdiags.Diagnostics) {
+		return state, nil
+	})
+}
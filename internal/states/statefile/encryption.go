@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statefile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform/internal/encryption"
+)
+
+// ReadEncrypted is like Read, but first checks whether r's contents are an
+// encrypted envelope (see encryption.IsEncrypted) and, if so, decrypts them
+// with dec before handing the plaintext JSON off to Read. Plaintext input is
+// passed through to Read unchanged, so existing unencrypted state files
+// continue to load with a nil or unconfigured dec.
+func ReadEncrypted(r io.Reader, dec encryption.StateEncryption) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state data: %w", err)
+	}
+
+	if dec == nil {
+		if encryption.IsEncrypted(data) {
+			return nil, encryption.ErrNoKeyProviderConfigured
+		}
+		return Read(bytes.NewReader(data))
+	}
+
+	plaintext, err := dec.DecryptState(data)
+	if err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(plaintext))
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statefile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform/internal/encryption"
+)
+
+// WriteEncrypted is like Write, but encrypts the serialized state with enc
+// before writing it out, the mirror image of ReadEncrypted. A nil or
+// unconfigured enc writes plaintext, the same as Write.
+//
+// No command in this tree persists state yet (show is read-only), so this
+// has no production caller today; it exists as the write-side counterpart
+// ReadEncrypted needs once a state-writing command threads a
+// StateEncryption through to it.
+func WriteEncrypted(s *File, w io.Writer, enc encryption.StateEncryption) error {
+	var buf bytes.Buffer
+	if err := Write(s, &buf); err != nil {
+		return fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	if enc == nil {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	ciphertext, err := enc.EncryptState(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt state: %w", err)
+	}
+	_, err = w.Write(ciphertext)
+	return err
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statefile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/encryption"
+	"github.com/hashicorp/terraform/internal/states"
+)
+
+func TestWriteEncrypted_roundTrip(t *testing.T) {
+	cases := map[string]encryption.Config{
+		"unconfigured": {},
+		"configured": {
+			State: &encryption.TargetConfig{
+				Method:            "aes_gcm",
+				KeyProvider:       "static",
+				KeyProviderConfig: map[string]string{"key": "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"},
+			},
+		},
+	}
+
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			enc, err := encryption.New(cfg)
+			if err != nil {
+				t.Fatalf("encryption.New failed: %s", err)
+			}
+
+			in := &File{
+				Serial:  1,
+				Lineage: "write-encrypted-round-trip",
+				State:   states.NewState(),
+			}
+
+			var buf bytes.Buffer
+			if err := WriteEncrypted(in, &buf, enc.StateEncryption()); err != nil {
+				t.Fatalf("WriteEncrypted failed: %s", err)
+			}
+
+			out, err := ReadEncrypted(&buf, enc.StateEncryption())
+			if err != nil {
+				t.Fatalf("ReadEncrypted failed: %s", err)
+			}
+			if out.Lineage != in.Lineage || out.Serial != in.Serial {
+				t.Fatalf("round trip mismatch: got %#v, want %#v", out, in)
+			}
+		})
+	}
+}